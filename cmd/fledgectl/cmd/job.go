@@ -0,0 +1,97 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"wwwin-github.cisco.com/eti/fledge/cmd/fledgectl/resources/job"
+)
+
+// newJobCommand builds the `fledgectl job` command group.
+func newJobCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "job",
+		Short: "manage federated learning jobs",
+	}
+
+	cmd.AddCommand(newJobCreateCommand())
+	cmd.AddCommand(newJobStatusCommand())
+	cmd.AddCommand(newJobLogsCommand())
+
+	return cmd
+}
+
+// newJobCreateCommand builds `fledgectl job create`.
+func newJobCreateCommand() *cobra.Command {
+	params := job.Params{}
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "submit a new job from a job spec file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params.CommonParams = common
+			return job.Create(params)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&params.JobFile, "job-file", "", "path to the job spec JSON file")
+	flags.StringVar(&params.DatasetId, "dataset-id", "", "id of the dataset the job trains on")
+	flags.StringVar(&params.CodeDir, "code-dir", "", "directory of task code to sign and upload alongside the job spec")
+	flags.StringVar(&params.SignKeyPath, "sign-key", "", "ed25519 private key used to sign --code-dir's manifest")
+	cmd.MarkFlagRequired("job-file")
+
+	return cmd
+}
+
+// newJobStatusCommand builds `fledgectl job status`.
+func newJobStatusCommand() *cobra.Command {
+	params := job.Params{}
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "show the per-agent task status reported for a job",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params.CommonParams = common
+			return job.Status(params)
+		},
+	}
+
+	cmd.Flags().StringVar(&params.JobId, "job-id", "", "id of the job to inspect")
+	cmd.MarkFlagRequired("job-id")
+
+	return cmd
+}
+
+// newJobLogsCommand builds `fledgectl job logs`.
+func newJobLogsCommand() *cobra.Command {
+	params := job.Params{}
+
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "show the tail of each agent's task log for a job",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params.CommonParams = common
+			return job.Logs(params)
+		},
+	}
+
+	cmd.Flags().StringVar(&params.JobId, "job-id", "", "id of the job to inspect")
+	cmd.MarkFlagRequired("job-id")
+
+	return cmd
+}
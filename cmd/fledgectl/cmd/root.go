@@ -0,0 +1,56 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cmd builds the fledgectl command tree and wires each subcommand
+// to the resources package that implements it.
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"wwwin-github.cisco.com/eti/fledge/cmd/fledgectl/resources"
+)
+
+const (
+	defaultHost = "localhost"
+	defaultPort = 8080
+)
+
+// common holds the --host/--port/--user flags shared by every subcommand
+// that talks to the apiserver.
+var common resources.CommonParams
+
+// Execute builds the fledgectl command tree and runs it.
+func Execute() error {
+	return NewCommand().Execute()
+}
+
+// NewCommand returns the root cobra command for fledgectl.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fledgectl",
+		Short: "fledgectl manages federated learning jobs and trusted signing keys",
+	}
+
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&common.Host, "host", defaultHost, "address of the apiserver")
+	flags.IntVar(&common.Port, "port", defaultPort, "port of the apiserver")
+	flags.StringVar(&common.User, "user", "", "user submitting the request")
+
+	cmd.AddCommand(newJobCommand())
+	cmd.AddCommand(newTrustCommand())
+
+	return cmd
+}
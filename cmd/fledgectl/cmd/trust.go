@@ -0,0 +1,112 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+
+	"wwwin-github.cisco.com/eti/fledge/cmd/fledgectl/resources/trust"
+)
+
+// defaultTrustDir matches fledgelet's default --trust-dir, so the two
+// sides work together out of the box.
+const defaultTrustDir = "/fledge/trust"
+
+// newTrustCommand builds the `fledgectl trust` command group for managing
+// the keyring of ed25519 public keys fledgelet agents trust to verify
+// signed task code manifests.
+func newTrustCommand() *cobra.Command {
+	var trustDir string
+
+	cmd := &cobra.Command{
+		Use:   "trust",
+		Short: "manage the keyring of trusted task-signing keys",
+	}
+
+	cmd.PersistentFlags().StringVar(&trustDir, "trust-dir", defaultTrustDir, "directory of trusted public keys")
+
+	cmd.AddCommand(newTrustAddCommand(&trustDir))
+	cmd.AddCommand(newTrustListCommand(&trustDir))
+	cmd.AddCommand(newTrustRemoveCommand(&trustDir))
+	cmd.AddCommand(newTrustGenerateCommand())
+
+	return cmd
+}
+
+// newTrustAddCommand builds `fledgectl trust add`.
+func newTrustAddCommand(trustDir *string) *cobra.Command {
+	var keyPath string
+
+	cmd := &cobra.Command{
+		Use:   "add",
+		Short: "add a public key to the trusted keyring",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return trust.Add(trust.Params{TrustDir: *trustDir, KeyPath: keyPath})
+		},
+	}
+
+	cmd.Flags().StringVar(&keyPath, "key", "", "path to the public key to trust")
+	cmd.MarkFlagRequired("key")
+
+	return cmd
+}
+
+// newTrustListCommand builds `fledgectl trust list`.
+func newTrustListCommand(trustDir *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "list the fingerprints of every trusted key",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return trust.List(trust.Params{TrustDir: *trustDir})
+		},
+	}
+}
+
+// newTrustRemoveCommand builds `fledgectl trust remove`.
+func newTrustRemoveCommand(trustDir *string) *cobra.Command {
+	var fingerprint string
+
+	cmd := &cobra.Command{
+		Use:   "remove",
+		Short: "remove a trusted key by fingerprint",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return trust.Remove(trust.Params{TrustDir: *trustDir, Fingerprint: fingerprint})
+		},
+	}
+
+	cmd.Flags().StringVar(&fingerprint, "fingerprint", "", "fingerprint of the key to remove")
+	cmd.MarkFlagRequired("fingerprint")
+
+	return cmd
+}
+
+// newTrustGenerateCommand builds `fledgectl trust generate`.
+func newTrustGenerateCommand() *cobra.Command {
+	var keyPath string
+
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "generate a new ed25519 signing key pair",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return trust.Generate(trust.Params{KeyPath: keyPath})
+		},
+	}
+
+	cmd.Flags().StringVar(&keyPath, "key", "", "path to write the new private key (and key+\".pub\")")
+	cmd.MarkFlagRequired("key")
+
+	return cmd
+}
@@ -19,10 +19,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 
 	"wwwin-github.cisco.com/eti/fledge/cmd/fledgectl/resources"
 	"wwwin-github.cisco.com/eti/fledge/pkg/openapi"
 	"wwwin-github.cisco.com/eti/fledge/pkg/restapi"
+	"wwwin-github.cisco.com/eti/fledge/pkg/sign"
+	"wwwin-github.cisco.com/eti/fledge/pkg/util"
 )
 
 type Params struct {
@@ -30,9 +34,26 @@ type Params struct {
 
 	JobFile   string
 	DatasetId string
+	JobId     string
+
+	// CodeDir is the directory holding the task code that gets zipped into
+	// TaskCodeFile before upload. When SignKeyPath is set, Create signs its
+	// contents before the code is packaged.
+	CodeDir string
+
+	// SignKeyPath is the ed25519 private key used to sign CodeDir's
+	// manifest, so a fledgelet with a matching trusted key will run it.
+	SignKeyPath string
 }
 
 func Create(params Params) error {
+	if params.SignKeyPath != "" {
+		if err := signCode(params); err != nil {
+			fmt.Printf("Failed to sign task code: %v\n", err)
+			return nil
+		}
+	}
+
 	data, err := ioutil.ReadFile(params.JobFile)
 	if err != nil {
 		fmt.Printf("Failed to read file %s: %v\n", params.JobFile, err)
@@ -53,8 +74,7 @@ func Create(params Params) error {
 	}
 	url := restapi.CreateURL(params.Host, params.Port, restapi.CreateJobEndpoint, uriMap)
 
-	// send post request
-	code, resp, err := restapi.HTTPPost(url, jobSpec, "application/json")
+	code, resp, err := postJobSpec(params, url, jobSpec)
 	if err != nil || restapi.CheckStatusCode(code) != nil {
 		fmt.Printf("Failed to create a job - code: %d, error: %v\n", code, err)
 		return nil
@@ -72,4 +92,153 @@ func Create(params Params) error {
 	fmt.Printf("\tstate: %s\n", jobStatus.State)
 
 	return nil
+}
+
+// Status prints the per-agent task status reported for a job, so operators
+// can see progress without SSHing into the agents' pods.
+func Status(params Params) error {
+	statuses, err := fetchTaskStatus(params)
+	if err != nil {
+		fmt.Printf("Failed to fetch task status: %v\n", err)
+		return nil
+	}
+
+	fmt.Printf("Task status for job %s\n", params.JobId)
+	for _, status := range statuses {
+		fmt.Printf("\tagent: %s\tphase: %s\texitCode: %d\terror: %s\n",
+			status.AgentId, status.Phase, status.ExitCode, status.Error)
+	}
+
+	return nil
+}
+
+// Logs prints the tail of each agent's task log for a job.
+func Logs(params Params) error {
+	statuses, err := fetchTaskStatus(params)
+	if err != nil {
+		fmt.Printf("Failed to fetch task logs: %v\n", err)
+		return nil
+	}
+
+	for _, status := range statuses {
+		fmt.Printf("==> agent %s <==\n%s\n", status.AgentId, status.LogTail)
+	}
+
+	return nil
+}
+
+// postJobSpec uploads jobSpec to url, attaching the manifest and signature
+// signCode wrote into params.CodeDir as multipart files alongside it when
+// the job's code was signed, so the apiserver can store them for fledgelet
+// to verify before running the task. Unsigned jobs keep the plain JSON
+// POST fledgectl has always sent.
+func postJobSpec(params Params, url string, jobSpec openapi.JobSpec) (int, []byte, error) {
+	if params.SignKeyPath == "" {
+		return restapi.HTTPPost(url, jobSpec, "application/json")
+	}
+
+	jobSpecData, err := json.Marshal(jobSpec)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to encode job spec: %v", err)
+	}
+
+	manifestData, err := ioutil.ReadFile(filepath.Join(params.CodeDir, util.TaskManifestFile))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read manifest: %v", err)
+	}
+
+	sigData, err := ioutil.ReadFile(filepath.Join(params.CodeDir, util.TaskManifestSigFile))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to read manifest signature: %v", err)
+	}
+
+	files := map[string][]byte{
+		util.JobSpecFile:         jobSpecData,
+		util.TaskManifestFile:    manifestData,
+		util.TaskManifestSigFile: sigData,
+	}
+
+	return restapi.HTTPPostMultipart(url, files)
+}
+
+// signCode computes a SHA-256 manifest of every file in params.CodeDir,
+// signs it with params.SignKeyPath, and writes the manifest and signature
+// back into CodeDir as util.TaskManifestFile/TaskManifestSigFile, from
+// where postJobSpec uploads them as their own multipart parts alongside the
+// job spec - a fledgelet's prepareTask verifies them before it will run
+// anything.
+func signCode(params Params) error {
+	priv, err := sign.LoadPrivateKey(params.SignKeyPath)
+	if err != nil {
+		return fmt.Errorf("failed to load signing key: %v", err)
+	}
+
+	files := make(map[string][]byte)
+	err = filepath.Walk(params.CodeDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(params.CodeDir, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		files[rel] = data
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk %s: %v", params.CodeDir, err)
+	}
+
+	manifest := sign.ComputeManifest(files)
+	manifestData, err := manifest.Bytes()
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %v", err)
+	}
+
+	sig, err := sign.Sign(manifest, priv)
+	if err != nil {
+		return fmt.Errorf("failed to sign manifest: %v", err)
+	}
+
+	manifestPath := filepath.Join(params.CodeDir, util.TaskManifestFile)
+	if err := ioutil.WriteFile(manifestPath, manifestData, util.FilePerm0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %v", err)
+	}
+
+	sigPath := filepath.Join(params.CodeDir, util.TaskManifestSigFile)
+	if err := ioutil.WriteFile(sigPath, sig, util.FilePerm0644); err != nil {
+		return fmt.Errorf("failed to write manifest signature: %v", err)
+	}
+
+	return nil
+}
+
+func fetchTaskStatus(params Params) ([]openapi.TaskStatus, error) {
+	uriMap := map[string]string{
+		"jobId": params.JobId,
+	}
+	url := restapi.CreateURL(params.Host, params.Port, restapi.GetTaskStatusEndpoint, uriMap)
+
+	code, resp, err := restapi.HTTPGet(url)
+	if err != nil || restapi.CheckStatusCode(code) != nil {
+		return nil, fmt.Errorf("code: %d, error: %v", code, err)
+	}
+
+	var statuses []openapi.TaskStatus
+	err = json.Unmarshal(resp, &statuses)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse response: %v", err)
+	}
+
+	return statuses, nil
 }
\ No newline at end of file
@@ -0,0 +1,87 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package trust implements `fledgectl trust`, for managing the keyring of
+// ed25519 public keys fledgelet agents trust to sign task code manifests.
+package trust
+
+import (
+	"fmt"
+
+	"wwwin-github.cisco.com/eti/fledge/pkg/sign"
+)
+
+type Params struct {
+	TrustDir string
+	KeyPath  string
+
+	// Fingerprint identifies a key already in TrustDir, for Remove.
+	Fingerprint string
+}
+
+// Add copies the public key at params.KeyPath into params.TrustDir.
+func Add(params Params) error {
+	fingerprint, err := sign.AddTrustedKey(params.TrustDir, params.KeyPath)
+	if err != nil {
+		fmt.Printf("Failed to add trusted key: %v\n", err)
+		return nil
+	}
+
+	fmt.Printf("Added trusted key %s\n", fingerprint)
+
+	return nil
+}
+
+// List prints the fingerprint of every key in params.TrustDir.
+func List(params Params) error {
+	keys, err := sign.LoadTrustedKeys(params.TrustDir)
+	if err != nil {
+		fmt.Printf("Failed to list trusted keys: %v\n", err)
+		return nil
+	}
+
+	for _, key := range keys {
+		fmt.Println(sign.Fingerprint(key))
+	}
+
+	return nil
+}
+
+// Remove deletes the trusted key identified by params.Fingerprint.
+func Remove(params Params) error {
+	if err := sign.RemoveTrustedKey(params.TrustDir, params.Fingerprint); err != nil {
+		fmt.Printf("Failed to remove trusted key %s: %v\n", params.Fingerprint, err)
+		return nil
+	}
+
+	fmt.Printf("Removed trusted key %s\n", params.Fingerprint)
+
+	return nil
+}
+
+// Generate creates a new ed25519 key pair at params.KeyPath (and
+// params.KeyPath+".pub"), for an operator to pass to
+// `fledgectl job create --sign-key` and `fledgectl trust add`.
+func Generate(params Params) error {
+	pub, err := sign.GenerateKey(params.KeyPath)
+	if err != nil {
+		fmt.Printf("Failed to generate signing key: %v\n", err)
+		return nil
+	}
+
+	fmt.Printf("Generated signing key %s (fingerprint %s)\n", params.KeyPath, sign.Fingerprint(pub))
+
+	return nil
+}
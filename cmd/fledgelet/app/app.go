@@ -0,0 +1,121 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package app
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultTaskStopGrace = 30 * time.Second
+
+	// defaultMaxProcs matches Drone's single-job-per-agent default; operators
+	// opt into higher concurrency explicitly via --max-procs.
+	defaultMaxProcs = 1
+
+	// defaultTrustDir matches the directory fledgectl's `trust add` writes
+	// keys to by default, so the two sides work together out of the box.
+	defaultTrustDir = "/fledge/trust"
+
+	// defaultRetryLimit of 0 retries the notifier connection forever.
+	defaultRetryLimit = 0
+
+	// defaultGpuCount assumes no GPU unless the operator tells us otherwise;
+	// fledgelet has no portable way to auto-detect GPU hardware.
+	defaultGpuCount = 0
+)
+
+// options holds the command-line configurable settings for fledgelet.
+type options struct {
+	apiserverEp   string
+	notifierEp    string
+	name          string
+	agentId       string
+	taskStopGrace time.Duration
+	maxProcs      int
+	gpuCount      int
+	trustDir      string
+
+	notifierCA     string
+	notifierCert   string
+	notifierKey    string
+	retryLimit     int
+	legacyNotifier bool
+}
+
+// Execute builds the fledgelet command and runs it.
+func Execute() error {
+	return NewCommand().Execute()
+}
+
+// NewCommand returns the root cobra command for fledgelet.
+func NewCommand() *cobra.Command {
+	opts := &options{}
+
+	cmd := &cobra.Command{
+		Use:   "fledgelet",
+		Short: "fledgelet runs federated learning tasks assigned to this agent",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return run(opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opts.apiserverEp, "apiserver", "", "address of the apiserver")
+	flags.StringVar(&opts.notifierEp, "notifier", "", "address of the notifier")
+	flags.StringVar(&opts.name, "name", "", "hostname of this agent")
+	flags.StringVar(&opts.agentId, "agent-id", "", "unique id of this agent")
+	flags.DurationVar(&opts.taskStopGrace, "task-stop-grace", defaultTaskStopGrace,
+		"grace period given to a task to exit after SIGTERM before it is killed")
+	flags.IntVar(&opts.maxProcs, "max-procs", defaultMaxProcs,
+		"maximum number of tasks this agent runs concurrently")
+	flags.IntVar(&opts.gpuCount, "gpu-count", defaultGpuCount,
+		"number of GPUs available to this agent, advertised to the apiserver for scheduling")
+	flags.StringVar(&opts.trustDir, "trust-dir", defaultTrustDir,
+		"directory of trusted public keys a task's code manifest must be signed by")
+	flags.StringVar(&opts.notifierCA, "notifier-ca", "", "CA certificate used to verify the notifier's certificate")
+	flags.StringVar(&opts.notifierCert, "notifier-cert", "", "client certificate used to authenticate with the notifier; enables TLS when set")
+	flags.StringVar(&opts.notifierKey, "notifier-key", "", "private key matching --notifier-cert")
+	flags.IntVar(&opts.retryLimit, "retry-limit", defaultRetryLimit,
+		"maximum number of notifier reconnect attempts before giving up; 0 retries forever")
+	flags.BoolVar(&opts.legacyNotifier, "legacy-notifier", false,
+		"use the one-way GetEvent stream and REST status callbacks instead of the bidirectional control stream")
+
+	return cmd
+}
+
+// run starts the task handler and blocks until fledgelet is asked to
+// terminate, at which point it drains any in-flight tasks before returning.
+func run(opts *options) error {
+	t := newTaskHandler(opts.apiserverEp, opts.notifierEp, opts.name, opts.agentId, opts.taskStopGrace, opts.maxProcs, opts.gpuCount, opts.trustDir,
+		opts.notifierCA, opts.notifierCert, opts.notifierKey, opts.retryLimit, opts.legacyNotifier)
+	t.start()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	<-sigCh
+
+	zap.S().Info("Received termination signal, draining in-flight tasks")
+	t.DrainAll()
+
+	return nil
+}
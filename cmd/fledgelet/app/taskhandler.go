@@ -17,50 +17,140 @@ package app
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	backoff "github.com/cenkalti/backoff/v4"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
+	"wwwin-github.cisco.com/eti/fledge/pkg/openapi"
 	pbNotify "wwwin-github.cisco.com/eti/fledge/pkg/proto/notification"
 	"wwwin-github.cisco.com/eti/fledge/pkg/restapi"
+	"wwwin-github.cisco.com/eti/fledge/pkg/runner"
+	"wwwin-github.cisco.com/eti/fledge/pkg/sign"
 	"wwwin-github.cisco.com/eti/fledge/pkg/util"
 )
 
 const (
 	workDir       = "/fledge/work"
-	pythonBin     = "python3"
-	taskPyFile    = "main.py"
 	logFilePrefix = "task"
 	logFileExt    = "log"
+
+	// defaultRunnerName is used when a task's config doesn't request a
+	// specific runner backend, keeping existing deployments working as-is.
+	defaultRunnerName = runner.ProcessRunnerName
+
+	// statusLogTailLines is how many trailing log lines are attached to a
+	// status report so operators get context without pulling the full log.
+	statusLogTailLines = 20
 )
 
+// taskContext carries the per-job state that used to live directly on
+// taskHandler (role, working directory, runner backend), so that multiple
+// jobs can run concurrently without clobbering each other's config.
+type taskContext struct {
+	jobId      string
+	role       string
+	workDir    string
+	runnerName string
+	runnerOpts openapi.RunnerSpec
+
+	r    runner.TaskRunner
+	done chan struct{}
+
+	// stopping is set by drainTask before stopping the runner, so the
+	// Run() goroutine in runTask can report STOPPED instead of EXITED_ERR
+	// for an intentional stop.
+	stopping int32 // atomic bool
+}
+
 type taskHandler struct {
 	apiserverEp string
 	notifierEp  string
 	name        string
 	agentId     string
 
+	// taskStopGrace is how long a task is given to exit after SIGTERM
+	// before fledgelet escalates to SIGKILL.
+	taskStopGrace time.Duration
+
+	// maxProcs bounds how many tasks this agent runs at once; jobs beyond
+	// that are queued until a running task frees its slot.
+	maxProcs int
+	sem      chan struct{}
+
+	// gpuCount is the number of GPUs this agent advertises to the
+	// apiserver for scheduling; fledgelet has no portable way to detect
+	// this itself, so it comes straight from the operator via --gpu-count.
+	gpuCount int
+
+	// trustDir holds the ed25519 public keys a task's code manifest
+	// signature must match before prepareTask will launch it.
+	trustDir string
+
+	// notifierCA, notifierCert and notifierKey configure TLS to the
+	// notifier: notifierCA alone enables server-authenticated TLS,
+	// notifierCert/notifierKey add a client certificate for mutual TLS.
+	// When neither is set the connection is insecure, matching earlier
+	// behavior for local development.
+	notifierCA   string
+	notifierCert string
+	notifierKey  string
+
+	// retryLimit bounds how many times doStart reconnects to the notifier
+	// before giving up; 0 means retry forever.
+	retryLimit int
+
+	// legacyNotifier keeps an agent on the one-way GetEvent stream and REST
+	// status callbacks during the migration to the bidirectional Control
+	// stream, which is the default.
+	legacyNotifier bool
+
+	queueMu sync.Mutex
+	queue   []string
+
 	stream pbNotify.EventRoute_GetEventClient
 
-	// role of the task given to fledgelet
-	role string
+	// controlStream is set once Control's Hello handshake succeeds; reads
+	// happen from doControl's single goroutine, writes (status reports from
+	// every job's goroutine) are serialized by controlMu.
+	controlStream pbNotify.EventRoute_ControlClient
+	controlMu     sync.Mutex
+
+	// tasks tracks the context of each running job so it can be stopped,
+	// updated, or drained on shutdown.
+	tasks sync.Map // jobId string -> *taskContext
 }
 
-func newTaskHandler(apiserverEp string, notifierEp string, name string, agentId string) *taskHandler {
+func newTaskHandler(apiserverEp string, notifierEp string, name string, agentId string, taskStopGrace time.Duration, maxProcs int, gpuCount int, trustDir string, notifierCA string, notifierCert string, notifierKey string, retryLimit int, legacyNotifier bool) *taskHandler {
 	return &taskHandler{
-		apiserverEp: apiserverEp,
-		notifierEp:  notifierEp,
-		name:        name,
-		agentId:     agentId,
+		apiserverEp:    apiserverEp,
+		notifierEp:     notifierEp,
+		name:           name,
+		agentId:        agentId,
+		taskStopGrace:  taskStopGrace,
+		maxProcs:       maxProcs,
+		sem:            make(chan struct{}, maxProcs),
+		gpuCount:       gpuCount,
+		trustDir:       trustDir,
+		notifierCA:     notifierCA,
+		notifierCert:   notifierCert,
+		notifierKey:    notifierKey,
+		retryLimit:     retryLimit,
+		legacyNotifier: legacyNotifier,
 	}
 }
 
@@ -71,16 +161,32 @@ func (t *taskHandler) start() {
 
 func (t *taskHandler) doStart() {
 	pauseTime := 10 * time.Second
+	attempt := 0
 
 	for {
 		expBackoff := backoff.NewExponentialBackOff()
-		expBackoff.MaxElapsedTime = 5 * time.Minute // max wait time: 5 minutes
+		expBackoff.MaxElapsedTime = 5 * time.Minute // max wait time per attempt
+		expBackoff.RandomizationFactor = 0.5        // jitter so a notifier restart isn't thundered by every agent reconnecting in lockstep
+
 		err := backoff.Retry(t.connect, expBackoff)
 		if err != nil {
-			zap.S().Fatalf("Cannot connect with notifier: %v", err)
+			attempt++
+			if t.retryLimit > 0 && attempt >= t.retryLimit {
+				zap.S().Fatalf("Cannot connect with notifier after %d attempts: %v", attempt, err)
+			}
+
+			zap.S().Warnf("Cannot connect with notifier (attempt %d): %v", attempt, err)
+			time.Sleep(pauseTime)
+			continue
 		}
 
-		t.do()
+		attempt = 0
+
+		if t.legacyNotifier {
+			t.do()
+		} else {
+			t.doControl()
+		}
 
 		// if connection is broken right after connection is made, this can cause
 		// too many connection/disconnection events. To migitage that, add some static
@@ -89,21 +195,75 @@ func (t *taskHandler) doStart() {
 	}
 }
 
+// dialOption builds the transport credentials for the notifier connection,
+// falling back to an insecure connection when no TLS material is
+// configured, matching earlier behavior for local development. --notifier-ca
+// alone is enough to enable server-authenticated TLS; --notifier-cert adds a
+// client certificate on top of that for mutual TLS.
+func (t *taskHandler) dialOption() (grpc.DialOption, error) {
+	if t.notifierCA == "" && t.notifierCert == "" {
+		return grpc.WithInsecure(), nil
+	}
+
+	tlsConfig := &tls.Config{}
+
+	if t.notifierCA != "" {
+		caData, err := ioutil.ReadFile(t.notifierCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read notifier CA: %v", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("failed to parse notifier CA %s", t.notifierCA)
+		}
+
+		tlsConfig.RootCAs = pool
+	}
+
+	if t.notifierCert != "" {
+		cert, err := tls.LoadX509KeyPair(t.notifierCert, t.notifierKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load notifier client certificate: %v", err)
+		}
+
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), nil
+}
+
 func (t *taskHandler) connect() error {
+	dialOpt, err := t.dialOption()
+	if err != nil {
+		// bad TLS configuration will never succeed on retry
+		return backoff.Permanent(err)
+	}
+
 	// dial server
-	conn, err := grpc.Dial(t.notifierEp, grpc.WithInsecure())
+	conn, err := grpc.Dial(t.notifierEp, dialOpt)
 	if err != nil {
 		zap.S().Debugf("Cannot connect with notifier: %v", err)
 		return err
 	}
 
 	client := pbNotify.NewEventRouteClient(conn)
+
+	if t.legacyNotifier {
+		return t.connectLegacy(client)
+	}
+
+	return t.connectControl(client)
+}
+
+// connectLegacy opens the one-way GetEvent stream used when fledgelet is
+// started with --legacy-notifier.
+func (t *taskHandler) connectLegacy(client pbNotify.EventRouteClient) error {
 	in := &pbNotify.AgentInfo{
 		Id:       t.agentId,
 		Hostname: t.name,
 	}
 
-	// setup notification stream
 	stream, err := client.GetEvent(context.Background(), in)
 	if err != nil {
 		zap.S().Debugf("Open stream error: %v", err)
@@ -111,11 +271,55 @@ func (t *taskHandler) connect() error {
 	}
 
 	t.stream = stream
-	zap.S().Infof("Connected with notifier at %s", t.notifierEp)
+	zap.S().Infof("Connected with notifier at %s (legacy event stream)", t.notifierEp)
 
 	return nil
 }
 
+// connectControl opens the bidirectional Control stream and sends the
+// Hello handshake advertising this agent's capabilities.
+func (t *taskHandler) connectControl(client pbNotify.EventRouteClient) error {
+	stream, err := client.Control(context.Background())
+	if err != nil {
+		zap.S().Debugf("Open control stream error: %v", err)
+		return err
+	}
+
+	hello := &pbNotify.ControlMessage{
+		Payload: &pbNotify.ControlMessage_Hello{
+			Hello: &pbNotify.Hello{
+				Agent: &pbNotify.AgentInfo{
+					Id:       t.agentId,
+					Hostname: t.name,
+				},
+				Capabilities: t.capabilities(),
+			},
+		},
+	}
+
+	if err := stream.Send(hello); err != nil {
+		zap.S().Debugf("Failed to advertise capabilities: %v", err)
+		return err
+	}
+
+	t.controlStream = stream
+	zap.S().Infof("Connected with notifier at %s (control stream)", t.notifierEp)
+
+	return nil
+}
+
+// capabilities describes what this agent can run, so the apiserver can
+// make informed scheduling decisions instead of blindly pushing work an
+// agent can't handle.
+func (t *taskHandler) capabilities() *pbNotify.Capabilities {
+	return &pbNotify.Capabilities{
+		Runners:     runner.Names(),
+		MaxProcs:    int32(t.maxProcs),
+		GpuCount:    int32(t.gpuCount),
+		CurrentLoad: int32(len(t.sem)),
+	}
+}
+
 func (t *taskHandler) do() {
 	for {
 		resp, err := t.stream.Recv()
@@ -130,6 +334,28 @@ func (t *taskHandler) do() {
 	zap.S().Info("Disconnected from notifier")
 }
 
+// doControl reads Event frames off the Control stream until it breaks,
+// playing the same role as do() for the legacy event stream.
+func (t *taskHandler) doControl() {
+	for {
+		msg, err := t.controlStream.Recv()
+		if err != nil {
+			zap.S().Errorf("Failed to receive control message: %v", err)
+			break
+		}
+
+		event := msg.GetEvent()
+		if event == nil {
+			zap.S().Warnf("Ignoring unexpected control message: %v", msg)
+			continue
+		}
+
+		t.dealWith(event)
+	}
+
+	zap.S().Info("Disconnected from notifier")
+}
+
 //newNotification acts as a handler and calls respective functions based on the response type to act on the received notifications.
 func (t *taskHandler) dealWith(in *pbNotify.Event) {
 	switch in.GetType() {
@@ -149,25 +375,153 @@ func (t *taskHandler) dealWith(in *pbNotify.Event) {
 	}
 }
 
-// startJob starts the application on the agent
+// startJob accepts a job for execution, gated by the --max-procs semaphore:
+// if a worker slot is free the job runs immediately, otherwise it is queued
+// until one frees up.
 func (t *taskHandler) startJob(jobId string) {
 	zap.S().Infof("Received start job request on job %s", jobId)
 
+	select {
+	case t.sem <- struct{}{}:
+		go t.runJob(jobId)
+	default:
+		zap.S().Warnf("Max concurrent tasks (%d) reached, queuing job %s", t.maxProcs, jobId)
+		t.queueMu.Lock()
+		t.queue = append(t.queue, jobId)
+		t.queueMu.Unlock()
+	}
+}
+
+// runJob downloads, prepares, and runs jobId, holding a worker slot until
+// the task process exits (or fails before one is ever started).
+func (t *taskHandler) runJob(jobId string) {
+	t.reportStatus(jobId, openapi.TaskPhaseDownloading, 0, nil)
+
 	filePaths, err := t.getTask(jobId)
 	if err != nil {
 		zap.S().Warnf("Failed to download payload: %v", err)
+		t.reportStatus(jobId, openapi.TaskPhaseExitedErr, -1, err)
+		t.release()
 		return
 	}
 
-	err = t.prepareTask(filePaths)
+	t.reportStatus(jobId, openapi.TaskPhasePreparing, 0, nil)
+
+	tc, err := t.prepareTask(jobId, filePaths)
 	if err != nil {
-		zap.S().Warnf("Failed to prepare task")
+		zap.S().Warnf("Failed to prepare task: %v", err)
+
+		phase := openapi.TaskPhaseExitedErr
+		var verifyErr *verificationError
+		if errors.As(err, &verifyErr) {
+			phase = openapi.TaskPhaseVerificationFailed
+		}
+
+		t.reportStatus(jobId, phase, -1, err)
+		t.release()
 		return
 	}
 
-	go t.runTask(jobId)
+	t.runTask(tc)
+}
+
+// reportStatus reports a task lifecycle status transition for jobId back
+// to the apiserver, attaching the tail of the task's log file for context.
+// When connected over the Control stream, the status is sent inline on it;
+// otherwise (--legacy-notifier) it is POSTed to the REST status endpoint.
+func (t *taskHandler) reportStatus(jobId string, phase openapi.TaskPhase, exitCode int, taskErr error) {
+	status := openapi.TaskStatus{
+		JobId:    jobId,
+		AgentId:  t.agentId,
+		Phase:    phase,
+		ExitCode: exitCode,
+		LogTail:  tailLogFile(logFilePath(jobId), statusLogTailLines),
+	}
+	if taskErr != nil {
+		status.Error = taskErr.Error()
+	}
+
+	if t.controlStream != nil {
+		t.reportStatusOverControl(status)
+		return
+	}
 
-	// TODO: implement updateTaskStatus method
+	uriMap := map[string]string{
+		"jobId":   jobId,
+		"agentId": t.agentId,
+	}
+	url := restapi.CreateURL(t.apiserverEp, restapi.UpdateTaskStatusEndpoint, uriMap)
+
+	code, _, err := restapi.HTTPPost(url, status, "application/json")
+	if err != nil || restapi.CheckStatusCode(code) != nil {
+		zap.S().Warnf("Failed to report status %s for job %s - code: %d, error: %v", phase, jobId, code, err)
+	}
+}
+
+// reportStatusOverControl sends status inline on the Control stream. Send
+// is called concurrently by every job's goroutine, so it is serialized by
+// controlMu - grpc streams don't allow concurrent sends.
+func (t *taskHandler) reportStatusOverControl(status openapi.TaskStatus) {
+	msg := &pbNotify.ControlMessage{
+		Payload: &pbNotify.ControlMessage_Status{
+			Status: &pbNotify.TaskStatus{
+				JobId:    status.JobId,
+				Phase:    string(status.Phase),
+				ExitCode: int32(status.ExitCode),
+				Error:    status.Error,
+				LogTail:  status.LogTail,
+			},
+		},
+	}
+
+	t.controlMu.Lock()
+	err := t.controlStream.Send(msg)
+	t.controlMu.Unlock()
+
+	if err != nil {
+		zap.S().Warnf("Failed to report status %s for job %s over control stream: %v", status.Phase, status.JobId, err)
+	}
+}
+
+// logFilePath returns the log file path for jobId's task process.
+func logFilePath(jobId string) string {
+	logFileName := fmt.Sprintf("%s-%s.%s", logFilePrefix, jobId, logFileExt)
+	return filepath.Join(util.LogDirPath, logFileName)
+}
+
+// tailLogFile returns the last n lines of the file at path, or "" if it
+// can't be read yet (e.g. the task hasn't started writing to it).
+func tailLogFile(path string, n int) string {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// release frees the worker slot held by a finished job and, if any job is
+// queued, immediately hands the slot to it instead of giving it back.
+func (t *taskHandler) release() {
+	t.queueMu.Lock()
+	var next string
+	if len(t.queue) > 0 {
+		next = t.queue[0]
+		t.queue = t.queue[1:]
+	}
+	t.queueMu.Unlock()
+
+	if next != "" {
+		go t.runJob(next)
+		return
+	}
+
+	<-t.sem
 }
 
 func (t *taskHandler) getTask(jobId string) ([]string, error) {
@@ -202,117 +556,338 @@ func (t *taskHandler) getTask(jobId string) ([]string, error) {
 	return filePaths, nil
 }
 
-func (t *taskHandler) prepareTask(filePaths []string) error {
-	err := os.MkdirAll(workDir, util.FilePerm0755)
+// prepareTask unpacks the downloaded task payload into a job-private working
+// directory (workDir/<jobId>) and returns a taskContext describing it,
+// rather than mutating taskHandler, so concurrent jobs can't clobber each
+// other's role or files.
+func (t *taskHandler) prepareTask(jobId string, filePaths []string) (*taskContext, error) {
+	jobWorkDir := filepath.Join(workDir, jobId)
+	err := os.MkdirAll(jobWorkDir, util.FilePerm0755)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var fileDataList []util.FileData
 	var file *os.File
 	configFilePath := ""
+	manifestFilePath := ""
+	sigFilePath := ""
 	configFound := false
 	codeFound := false
+	manifestFound := false
+	sigFound := false
 	for _, filePath := range filePaths {
-		if strings.Contains(filePath, util.TaskConfigFile) {
+		switch {
+		case strings.Contains(filePath, util.TaskManifestSigFile):
+			sigFound = true
+			sigFilePath = filePath
+		case strings.Contains(filePath, util.TaskManifestFile):
+			manifestFound = true
+			manifestFilePath = filePath
+		case strings.Contains(filePath, util.TaskConfigFile):
 			configFound = true
-
 			configFilePath = filePath
-		} else if strings.Contains(filePath, util.TaskCodeFile) {
+		case strings.Contains(filePath, util.TaskCodeFile):
 			codeFound = true
 
 			file, err = os.Open(filePath)
 			if err != nil {
-				return fmt.Errorf("failed to open %s: %v", filePath, err)
+				return nil, fmt.Errorf("failed to open %s: %v", filePath, err)
 			}
 
 			fileDataList, err = util.UnzipFile(file)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
 	}
 
 	if !configFound || !codeFound {
-		return fmt.Errorf("either %s or %s not found", util.TaskConfigFile, util.TaskCodeFile)
+		return nil, fmt.Errorf("either %s or %s not found", util.TaskConfigFile, util.TaskCodeFile)
 	}
 
-	// copy config file to work directory
+	if err := t.verifyManifest(manifestFound, manifestFilePath, sigFound, sigFilePath, fileDataList); err != nil {
+		return nil, &verificationError{err}
+	}
+
+	// copy config file to the job's work directory
 	input, err := ioutil.ReadFile(configFilePath)
 	if err != nil {
-		return fmt.Errorf("failed to open config file %s: %v", configFilePath, err)
+		return nil, fmt.Errorf("failed to open config file %s: %v", configFilePath, err)
 	}
 
-	dstFilePath := filepath.Join(workDir, util.TaskConfigFile)
+	dstFilePath := filepath.Join(jobWorkDir, util.TaskConfigFile)
 	err = ioutil.WriteFile(dstFilePath, input, util.FilePerm0644)
 	if err != nil {
-		return fmt.Errorf("failed to copy config file: %v", err)
+		return nil, fmt.Errorf("failed to copy config file: %v", err)
 	}
 
 	type tmpStruct struct {
-		Role string `json:"role"`
+		Role   string             `json:"role"`
+		Runner openapi.RunnerSpec `json:"runner"`
 	}
 
 	tmp := tmpStruct{}
 
 	err = json.Unmarshal(input, &tmp)
 	if err != nil {
-		return fmt.Errorf("failed to parse role")
+		return nil, fmt.Errorf("failed to parse role")
+	}
+
+	runnerName := tmp.Runner.Name
+	if runnerName == "" {
+		runnerName = defaultRunnerName
 	}
-	t.role = tmp.Role
 
-	// copy code files to work directory
+	// copy code files to the job's work directory
 	for _, fileData := range fileDataList {
-		dirPath := filepath.Join(workDir, filepath.Dir(fileData.FullName))
+		dirPath := filepath.Join(jobWorkDir, filepath.Dir(fileData.FullName))
 		err := os.MkdirAll(dirPath, util.FilePerm0755)
 		if err != nil {
-			return fmt.Errorf("failed to create directory: %v", err)
+			return nil, fmt.Errorf("failed to create directory: %v", err)
 		}
 
 		filePath := filepath.Join(dirPath, fileData.BaseName)
 		err = ioutil.WriteFile(filePath, []byte(fileData.Data), util.FilePerm0644)
 		if err != nil {
-			return fmt.Errorf("failed to unzip file %s: %v", filePath, err)
+			return nil, fmt.Errorf("failed to unzip file %s: %v", filePath, err)
 		}
 	}
 
-	return nil
+	return &taskContext{
+		jobId:      jobId,
+		role:       tmp.Role,
+		workDir:    jobWorkDir,
+		runnerName: runnerName,
+		runnerOpts: tmp.Runner,
+	}, nil
+}
+
+// verificationError marks a prepareTask failure caused by a manifest or
+// signature mismatch, so runJob can report VERIFICATION_FAILED instead of
+// the generic EXITED_ERR phase.
+type verificationError struct {
+	err error
+}
+
+func (e *verificationError) Error() string { return e.err.Error() }
+func (e *verificationError) Unwrap() error { return e.err }
+
+// verifyManifest checks that the downloaded task code matches a manifest
+// signed by a key in t.trustDir, before any of it is unpacked into the job's
+// work directory and executed. Anyone who can spoof or MITM the apiserver should not be able
+// to get fledgelet to run arbitrary code.
+func (t *taskHandler) verifyManifest(manifestFound bool, manifestFilePath string, sigFound bool, sigFilePath string, fileDataList []util.FileData) error {
+	if !manifestFound || !sigFound {
+		return fmt.Errorf("task code is missing %s or %s", util.TaskManifestFile, util.TaskManifestSigFile)
+	}
+
+	manifestData, err := ioutil.ReadFile(manifestFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest: %v", err)
+	}
+
+	sigData, err := ioutil.ReadFile(sigFilePath)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest signature: %v", err)
+	}
+
+	manifest := &sign.Manifest{}
+	if err := json.Unmarshal(manifestData, manifest); err != nil {
+		return fmt.Errorf("failed to parse manifest: %v", err)
+	}
+
+	trustedKeys, err := sign.LoadTrustedKeys(t.trustDir)
+	if err != nil {
+		return fmt.Errorf("failed to load trusted keys: %v", err)
+	}
+
+	if err := sign.VerifySignature(manifest, sigData, trustedKeys); err != nil {
+		return err
+	}
+
+	files := make(map[string][]byte, len(fileDataList))
+	for _, fileData := range fileDataList {
+		files[fileData.FullName] = []byte(fileData.Data)
+	}
+
+	return manifest.Verify(files)
+}
+
+// dequeueJob removes jobId from the pending queue if it is waiting there for
+// a worker slot, reporting whether it was found.
+func (t *taskHandler) dequeueJob(jobId string) bool {
+	t.queueMu.Lock()
+	defer t.queueMu.Unlock()
+
+	for i, id := range t.queue {
+		if id == jobId {
+			t.queue = append(t.queue[:i], t.queue[i+1:]...)
+			return true
+		}
+	}
+
+	return false
 }
 
+// stopJob stops jobId, giving it taskStopGrace to exit on its own before it
+// is killed if it is already running, or simply removing it from the
+// pending queue if it hasn't started yet. The drain itself runs in its own
+// goroutine so a slow-to-stop task doesn't block dealWith from processing
+// the next notifier frame.
 func (t *taskHandler) stopJob(jobId string) {
-	zap.S().Infof("not yet implemented; received stop job request on job %s", jobId)
+	zap.S().Infof("Received stop job request on job %s", jobId)
+
+	if v, ok := t.tasks.Load(jobId); ok {
+		go t.drainTask(v.(*taskContext))
+		return
+	}
+
+	if t.dequeueJob(jobId) {
+		zap.S().Infof("Removed queued job %s before it started running", jobId)
+		t.reportStatus(jobId, openapi.TaskPhaseStopped, 0, nil)
+		return
+	}
+
+	zap.S().Warnf("No running or queued task found for job %s", jobId)
 }
 
-func (t *taskHandler) updateJob(jobId string) (string, error) {
-	zap.S().Infof("not yet implemented; received update job request on job %s", jobId)
-	return "", nil
+// updateJob stops the currently running task for jobId, or removes it from
+// the pending queue if it hasn't started yet, then fetches and launches the
+// updated task in its place. Like stopJob, the drain and restart run in
+// their own goroutine so dealWith isn't blocked waiting for them.
+func (t *taskHandler) updateJob(jobId string) {
+	zap.S().Infof("Received update job request on job %s", jobId)
+
+	if v, ok := t.tasks.Load(jobId); ok {
+		tc := v.(*taskContext)
+		go func() {
+			t.drainTask(tc)
+			t.startJob(jobId)
+		}()
+		return
+	}
+
+	t.dequeueJob(jobId)
+	t.startJob(jobId)
 }
 
-func (t *taskHandler) runTask(jobId string) {
-	taskFilePath := filepath.Join(workDir, t.role, taskPyFile)
-	configFilePath := filepath.Join(workDir, util.TaskConfigFile)
+// drainTask asks tc's runner to stop, giving it up to taskStopGrace before
+// the runner escalates to a forced kill - modeled on the trap/kill pattern
+// most container runtimes use to stop a task without leaving it orphaned.
+func (t *taskHandler) drainTask(tc *taskContext) {
+	if tc.r == nil {
+		return
+	}
 
-	// TODO: run the task in different user group with less privilege
-	cmd := exec.Command(pythonBin, taskFilePath, configFilePath)
-	zap.S().Debugf("Running task with command: %v", cmd)
+	zap.S().Infof("Stopping task for job %s", tc.jobId)
 
-	logFileName := fmt.Sprintf("%s-%s.%s", logFilePrefix, jobId, logFileExt)
-	logFilePath := filepath.Join(util.LogDirPath, logFileName)
-	file, err := os.Create(logFilePath)
+	atomic.StoreInt32(&tc.stopping, 1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.taskStopGrace)
+	defer cancel()
+
+	if err := tc.r.Stop(ctx); err != nil {
+		zap.S().Warnf("Failed to stop job %s: %v", tc.jobId, err)
+	}
+
+	<-tc.done
+}
+
+// DrainAll gracefully stops every in-flight task concurrently, so shutdown
+// takes at most taskStopGrace regardless of how many tasks are running. It
+// is called on fledgelet shutdown so that pod terminations don't leave
+// orphaned task processes writing to closed log files.
+func (t *taskHandler) DrainAll() {
+	var wg sync.WaitGroup
+
+	t.tasks.Range(func(key, value interface{}) bool {
+		tc := value.(*taskContext)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			t.drainTask(tc)
+		}()
+
+		return true
+	})
+
+	wg.Wait()
+}
+
+// runTask builds tc's runner backend, prepares and launches it, and holds
+// tc's worker slot until the task exits.
+func (t *taskHandler) runTask(tc *taskContext) {
+	r, err := runner.New(tc.runnerName)
 	if err != nil {
-		zap.S().Errorf("Failed to create a log file: %v", err)
+		zap.S().Errorf("Failed to create task runner for job %s: %v", tc.jobId, err)
+		t.reportStatus(tc.jobId, openapi.TaskPhaseExitedErr, -1, err)
+		t.release()
 		return
 	}
-	defer file.Close()
 
-	cmd.Stdout = file
-	cmd.Stderr = file
+	rtc := &runner.TaskContext{
+		JobId:   tc.jobId,
+		Role:    tc.role,
+		WorkDir: tc.workDir,
+		LogPath: logFilePath(tc.jobId),
+		Image:   tc.runnerOpts.Image,
+		Cpu:     tc.runnerOpts.Cpu,
+		Mem:     tc.runnerOpts.Mem,
+		Gpu:     tc.runnerOpts.Gpu,
+		User:    tc.runnerOpts.User,
+	}
 
-	err = cmd.Start()
-	if err != nil {
-		zap.S().Errorf("Failed to start task: %v", err)
+	if err := r.Prepare(context.Background(), rtc); err != nil {
+		zap.S().Errorf("Failed to prepare task runner for job %s: %v", tc.jobId, err)
+		t.reportStatus(tc.jobId, openapi.TaskPhaseExitedErr, -1, err)
+		t.release()
 		return
 	}
 
-	zap.S().Infof("Started task for job %s successfully", jobId)
+	tc.r = r
+	tc.done = make(chan struct{})
+	t.tasks.Store(tc.jobId, tc)
+	t.reportStatus(tc.jobId, openapi.TaskPhaseRunning, 0, nil)
+
+	go func() {
+		runErr := r.Run(context.Background())
+
+		phase := openapi.TaskPhaseExitedOk
+		switch {
+		case atomic.LoadInt32(&tc.stopping) == 1:
+			phase = openapi.TaskPhaseStopped
+			zap.S().Infof("Task for job %s stopped", tc.jobId)
+		case runErr != nil:
+			phase = openapi.TaskPhaseExitedErr
+			zap.S().Warnf("Task for job %s exited with error: %v", tc.jobId, runErr)
+		default:
+			zap.S().Infof("Task for job %s exited successfully", tc.jobId)
+		}
+
+		t.reportStatus(tc.jobId, phase, exitCode(runErr), runErr)
+
+		close(tc.done)
+		t.tasks.Delete(tc.jobId)
+		t.release()
+	}()
+
+	zap.S().Infof("Started task for job %s successfully", tc.jobId)
+}
+
+// exitCode extracts the task's process exit code from the error returned by
+// a TaskRunner's Run, defaulting to 0 for a clean exit and -1 if the runner
+// failed before the task process could report one.
+func exitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+
+	return -1
 }
@@ -0,0 +1,35 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+// RunnerSpec selects the task-runner backend a fledgelet agent should use to
+// execute a job's task, plus any per-backend resource options. It rides
+// along in the task config fledgectl uploads and fledgelet downloads via
+// getTask.
+type RunnerSpec struct {
+	// Name is one of "process" (default), "docker", "podman", "containerd".
+	Name string `json:"name,omitempty"`
+
+	Image string `json:"image,omitempty"`
+	Cpu   string `json:"cpu,omitempty"`
+	Mem   string `json:"mem,omitempty"`
+	Gpu   int    `json:"gpu,omitempty"`
+
+	// User overrides the container user the docker/podman/containerd
+	// runners pass as `--user` in place of the default unprivileged
+	// 65534:65534. Ignored by the process runner.
+	User string `json:"user,omitempty"`
+}
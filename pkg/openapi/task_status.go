@@ -0,0 +1,47 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+// TaskPhase is a lifecycle phase a task on a fledgelet agent moves through,
+// reported to the apiserver so operators have visibility into a job without
+// SSHing into its pods.
+type TaskPhase string
+
+const (
+	TaskPhaseDownloading TaskPhase = "DOWNLOADING"
+	TaskPhasePreparing   TaskPhase = "PREPARING"
+	TaskPhaseRunning     TaskPhase = "RUNNING"
+	TaskPhaseExitedOk    TaskPhase = "EXITED_OK"
+	TaskPhaseExitedErr   TaskPhase = "EXITED_ERR"
+	TaskPhaseStopped     TaskPhase = "STOPPED"
+
+	// TaskPhaseVerificationFailed means fledgelet refused to run the task
+	// because its code manifest's signature, or a file's digest within it,
+	// could not be verified against a trusted key.
+	TaskPhaseVerificationFailed TaskPhase = "VERIFICATION_FAILED"
+)
+
+// TaskStatus is the payload a fledgelet agent posts to the apiserver at each
+// stage of a task's lifecycle, and that fledgectl reads back to show
+// per-agent progress.
+type TaskStatus struct {
+	JobId    string    `json:"jobId"`
+	AgentId  string    `json:"agentId"`
+	Phase    TaskPhase `json:"phase"`
+	ExitCode int       `json:"exitCode,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	LogTail  string    `json:"logTail,omitempty"`
+}
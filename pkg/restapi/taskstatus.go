@@ -0,0 +1,26 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package restapi
+
+const (
+	// UpdateTaskStatusEndpoint is where a fledgelet agent posts task
+	// lifecycle status transitions for a job back to the apiserver.
+	UpdateTaskStatusEndpoint = "tasks/{jobId}/agents/{agentId}/status"
+
+	// GetTaskStatusEndpoint returns the aggregated per-agent task status
+	// for a job, used by fledgectl's job status/logs subcommands.
+	GetTaskStatusEndpoint = "tasks/{jobId}/status"
+)
@@ -0,0 +1,143 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"wwwin-github.cisco.com/eti/fledge/pkg/util"
+)
+
+// defaultContainerUser is nobody:nogroup's numeric uid:gid, used when the
+// task doesn't request a specific container user. A numeric id works for
+// any image, unlike a named user ("nonroot") that most images don't define.
+const defaultContainerUser = "65534:65534"
+
+// dockerCompatRunner drives any docker CLI-compatible runtime (docker,
+// podman, nerdctl for containerd) to run a task inside a container. This
+// gives operators isolation for untrusted trainer code - the current TODO
+// in the process runner about running under a less-privileged user is
+// closed here by running as a non-root container user - plus a path to
+// GPU-enabled or language-agnostic trainers.
+type dockerCompatRunner struct {
+	bin string // "docker", "podman", or "nerdctl"
+
+	tc        *TaskContext
+	container string
+	exited    chan struct{}
+
+	// started is closed once the container's `run` command has actually
+	// been launched, or Run has failed before launching it, so Stop can
+	// wait for that instead of issuing a stop against a container that
+	// isn't launched yet.
+	started chan struct{}
+}
+
+func (r *dockerCompatRunner) Prepare(ctx context.Context, tc *TaskContext) error {
+	if tc.Image == "" {
+		return fmt.Errorf("%s runner requires an image", r.bin)
+	}
+
+	r.tc = tc
+	r.exited = make(chan struct{})
+	r.started = make(chan struct{})
+	r.container = fmt.Sprintf("fledge-%s", tc.JobId)
+
+	pull := exec.CommandContext(ctx, r.bin, "pull", tc.Image)
+	if out, err := pull.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to pull image %s: %v: %s", tc.Image, err, out)
+	}
+
+	return nil
+}
+
+func (r *dockerCompatRunner) Run(ctx context.Context) error {
+	user := r.tc.User
+	if user == "" {
+		user = defaultContainerUser
+	}
+
+	args := []string{
+		"run", "--name", r.container, "--rm",
+		"--user", user,
+		"-v", fmt.Sprintf("%s:%s", r.tc.WorkDir, r.tc.WorkDir),
+		"-w", filepath.Join(r.tc.WorkDir, r.tc.Role),
+	}
+
+	if r.tc.Cpu != "" {
+		args = append(args, "--cpus", r.tc.Cpu)
+	}
+	if r.tc.Mem != "" {
+		args = append(args, "--memory", r.tc.Mem)
+	}
+	if r.tc.Gpu > 0 {
+		args = append(args, "--gpus", fmt.Sprintf("%d", r.tc.Gpu))
+	}
+
+	args = append(args, r.tc.Image, pythonBin, taskPyFile, filepath.Join(r.tc.WorkDir, util.TaskConfigFile))
+
+	cmd := exec.Command(r.bin, args...)
+	zap.S().Debugf("Running task with command: %v", cmd)
+
+	file, err := os.Create(r.tc.LogPath)
+	if err != nil {
+		close(r.started)
+		close(r.exited)
+		return fmt.Errorf("failed to create a log file: %v", err)
+	}
+	defer file.Close()
+
+	cmd.Stdout = file
+	cmd.Stderr = file
+
+	if err := cmd.Start(); err != nil {
+		close(r.started)
+		close(r.exited)
+		return fmt.Errorf("failed to start task container: %v", err)
+	}
+
+	close(r.started)
+
+	err = cmd.Wait()
+	close(r.exited)
+
+	return err
+}
+
+func (r *dockerCompatRunner) Stop(ctx context.Context) error {
+	select {
+	case <-r.started:
+	case <-ctx.Done():
+		return fmt.Errorf("task did not start before the stop grace period elapsed")
+	}
+
+	if err := exec.Command(r.bin, "stop", r.container).Run(); err != nil {
+		zap.S().Warnf("%s stop %s failed: %v", r.bin, r.container, err)
+	}
+
+	select {
+	case <-r.exited:
+		return nil
+	case <-ctx.Done():
+		return exec.Command(r.bin, "kill", r.container).Run()
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+// ContainerdRunnerName is the registered name of the containerd runner. It
+// drives containerd through nerdctl, its docker-CLI-compatible frontend,
+// rather than the low-level ctr tool.
+const ContainerdRunnerName = "containerd"
+
+func init() {
+	Register(ContainerdRunnerName, func() TaskRunner { return &dockerCompatRunner{bin: "nerdctl"} })
+}
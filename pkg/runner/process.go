@@ -0,0 +1,120 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"wwwin-github.cisco.com/eti/fledge/pkg/util"
+)
+
+const (
+	// ProcessRunnerName is the registered name of the process runner.
+	ProcessRunnerName = "process"
+
+	pythonBin  = "python3"
+	taskPyFile = "main.py"
+)
+
+func init() {
+	Register(ProcessRunnerName, func() TaskRunner { return &processRunner{} })
+}
+
+// processRunner runs a task as a plain host process - fledgelet's original
+// behavior, and the default so existing deployments don't need a container
+// runtime to keep working.
+type processRunner struct {
+	tc     *TaskContext
+	cmd    *exec.Cmd
+	exited chan struct{}
+
+	// started is closed once Run has started the process or failed before
+	// doing so, so Stop can tell a task that hasn't spawned its process yet from one
+	// that has already exited, instead of silently no-oping on a nil cmd.
+	started chan struct{}
+}
+
+func (r *processRunner) Prepare(ctx context.Context, tc *TaskContext) error {
+	r.tc = tc
+	r.exited = make(chan struct{})
+	r.started = make(chan struct{})
+
+	return nil
+}
+
+func (r *processRunner) Run(ctx context.Context) error {
+	taskFilePath := filepath.Join(r.tc.WorkDir, r.tc.Role, taskPyFile)
+	configFilePath := filepath.Join(r.tc.WorkDir, util.TaskConfigFile)
+
+	// TODO: run the task in different user group with less privilege
+	cmd := exec.Command(pythonBin, taskFilePath, configFilePath)
+	zap.S().Debugf("Running task with command: %v", cmd)
+
+	file, err := os.Create(r.tc.LogPath)
+	if err != nil {
+		close(r.started)
+		close(r.exited)
+		return fmt.Errorf("failed to create a log file: %v", err)
+	}
+	defer file.Close()
+
+	cmd.Stdout = file
+	cmd.Stderr = file
+
+	if err := cmd.Start(); err != nil {
+		close(r.started)
+		close(r.exited)
+		return fmt.Errorf("failed to start task: %v", err)
+	}
+
+	r.cmd = cmd
+	close(r.started)
+
+	err = cmd.Wait()
+	close(r.exited)
+
+	return err
+}
+
+func (r *processRunner) Stop(ctx context.Context) error {
+	select {
+	case <-r.started:
+	case <-ctx.Done():
+		return fmt.Errorf("task did not start before the stop grace period elapsed")
+	}
+
+	if r.cmd == nil || r.cmd.Process == nil {
+		return nil
+	}
+
+	if err := r.cmd.Process.Signal(syscall.SIGTERM); err != nil {
+		return fmt.Errorf("failed to send SIGTERM: %v", err)
+	}
+
+	select {
+	case <-r.exited:
+		return nil
+	case <-ctx.Done():
+		return r.cmd.Process.Kill()
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package runner abstracts how a task's code actually executes, so
+// fledgelet isn't hardwired to a bare python3 process. Backends register
+// themselves with Register from an init(), and fledgelet picks one per job
+// via New(name).
+package runner
+
+import (
+	"context"
+	"fmt"
+)
+
+// TaskContext describes the task a TaskRunner should prepare and execute.
+type TaskContext struct {
+	JobId   string
+	Role    string
+	WorkDir string
+	LogPath string
+
+	// Image, Cpu, Mem, Gpu and User are only consulted by container-based
+	// runners; the process runner ignores them.
+	Image string
+	Cpu   string
+	Mem   string
+	Gpu   int
+	User  string
+}
+
+// TaskRunner isolates how a task's code actually executes - as a bare host
+// process, or inside a container runtime - behind a common lifecycle.
+type TaskRunner interface {
+	// Prepare readies the runner to execute the task described by tc (e.g.
+	// pulling a container image), but does not start it yet.
+	Prepare(ctx context.Context, tc *TaskContext) error
+
+	// Run starts the task and blocks until it exits.
+	Run(ctx context.Context) error
+
+	// Stop signals the running task to terminate, escalating to a forced
+	// kill once ctx is done. It returns once the task has exited or the kill
+	// has been issued, or with an error if the task was not started before
+	// ctx is done.
+	Stop(ctx context.Context) error
+}
+
+// Factory builds a fresh TaskRunner for a backend.
+type Factory func() TaskRunner
+
+var factories = map[string]Factory{}
+
+// Register makes a TaskRunner backend available under name. It is meant to
+// be called from an init() in each backend's file.
+func Register(name string, factory Factory) {
+	factories[name] = factory
+}
+
+// New returns a fresh TaskRunner for the given backend name.
+func New(name string) (TaskRunner, error) {
+	factory, ok := factories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown task runner: %s", name)
+	}
+
+	return factory(), nil
+}
+
+// Names returns the registered backend names, so an agent can advertise
+// what it's able to run when it connects to the notifier.
+func Names() []string {
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+
+	return names
+}
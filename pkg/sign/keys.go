@@ -0,0 +1,135 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"wwwin-github.cisco.com/eti/fledge/pkg/util"
+)
+
+// GenerateKey creates a new ed25519 key pair and writes the raw private and
+// public key bytes to privPath and privPath+".pub".
+func GenerateKey(privPath string) (ed25519.PublicKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %v", err)
+	}
+
+	if err := ioutil.WriteFile(privPath, priv, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write private key: %v", err)
+	}
+
+	if err := ioutil.WriteFile(privPath+".pub", pub, util.FilePerm0644); err != nil {
+		return nil, fmt.Errorf("failed to write public key: %v", err)
+	}
+
+	return pub, nil
+}
+
+// LoadPrivateKey reads a raw ed25519 private key from path.
+func LoadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) != ed25519.PrivateKeySize {
+		return nil, fmt.Errorf("%s is not a valid ed25519 private key", path)
+	}
+
+	return ed25519.PrivateKey(data), nil
+}
+
+// LoadPublicKey reads a raw ed25519 public key from path.
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%s is not a valid ed25519 public key", path)
+	}
+
+	return ed25519.PublicKey(data), nil
+}
+
+// LoadTrustedKeys loads every public key file in a trust directory (one raw
+// key per file) into a keyring used to verify task manifests.
+func LoadTrustedKeys(trustDir string) ([]ed25519.PublicKey, error) {
+	entries, err := ioutil.ReadDir(trustDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trust directory %s: %v", trustDir, err)
+	}
+
+	keys := make([]ed25519.PublicKey, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		pub, err := LoadPublicKey(filepath.Join(trustDir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load trusted key %s: %v", entry.Name(), err)
+		}
+
+		keys = append(keys, pub)
+	}
+
+	return keys, nil
+}
+
+// Fingerprint returns a short, stable identifier for pub, used to name it
+// inside a trust directory and to display it in `fledgectl trust list`.
+func Fingerprint(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// AddTrustedKey copies the public key at pubKeyPath into trustDir, named by
+// its fingerprint, so it is picked up by LoadTrustedKeys.
+func AddTrustedKey(trustDir string, pubKeyPath string) (string, error) {
+	pub, err := LoadPublicKey(pubKeyPath)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(trustDir, util.FilePerm0755); err != nil {
+		return "", fmt.Errorf("failed to create trust directory: %v", err)
+	}
+
+	fingerprint := Fingerprint(pub)
+	dst := filepath.Join(trustDir, fingerprint)
+	if err := ioutil.WriteFile(dst, pub, util.FilePerm0644); err != nil {
+		return "", fmt.Errorf("failed to add trusted key: %v", err)
+	}
+
+	return fingerprint, nil
+}
+
+// RemoveTrustedKey removes the trusted key with the given fingerprint from
+// trustDir.
+func RemoveTrustedKey(trustDir string, fingerprint string) error {
+	return os.Remove(filepath.Join(trustDir, fingerprint))
+}
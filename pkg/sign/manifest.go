@@ -0,0 +1,103 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sign computes and verifies ed25519-signed manifests for a task's
+// code bundle, so a fledgelet agent can refuse to run code that didn't come
+// from an operator it trusts.
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Manifest is the SHA-256 digest of every file in a task's code bundle.
+// encoding/json sorts map keys on marshal, so Bytes() is deterministic and
+// safe to sign.
+type Manifest struct {
+	Files map[string]string `json:"files"` // file name -> hex sha256 digest
+}
+
+// ComputeManifest hashes every entry in files (name -> contents).
+func ComputeManifest(files map[string][]byte) *Manifest {
+	m := &Manifest{Files: make(map[string]string, len(files))}
+	for name, data := range files {
+		sum := sha256.Sum256(data)
+		m.Files[name] = hex.EncodeToString(sum[:])
+	}
+
+	return m
+}
+
+// Bytes returns the canonical, deterministic encoding of m that is signed
+// and verified.
+func (m *Manifest) Bytes() ([]byte, error) {
+	return json.Marshal(m)
+}
+
+// Verify reports whether files contains exactly the set of files recorded
+// in the manifest and that each one matches its recorded digest, so a
+// re-unzipped task bundle can be checked for tampering (including files
+// silently dropped from the bundle) after its signature has already been
+// validated.
+func (m *Manifest) Verify(files map[string][]byte) error {
+	if len(files) != len(m.Files) {
+		return fmt.Errorf("bundle has %d file(s), manifest lists %d", len(files), len(m.Files))
+	}
+
+	for name, data := range files {
+		want, ok := m.Files[name]
+		if !ok {
+			return fmt.Errorf("%s is not listed in the manifest", name)
+		}
+
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != want {
+			return fmt.Errorf("%s does not match its manifest digest", name)
+		}
+	}
+
+	return nil
+}
+
+// Sign signs m's canonical bytes with priv.
+func Sign(m *Manifest, priv ed25519.PrivateKey) ([]byte, error) {
+	data, err := m.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return ed25519.Sign(priv, data), nil
+}
+
+// VerifySignature reports whether sig is a valid signature over m's
+// canonical bytes by any key in trustedKeys.
+func VerifySignature(m *Manifest, sig []byte, trustedKeys []ed25519.PublicKey) error {
+	data, err := m.Bytes()
+	if err != nil {
+		return err
+	}
+
+	for _, pub := range trustedKeys {
+		if ed25519.Verify(pub, data, sig) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("manifest signature does not match any trusted key")
+}
@@ -0,0 +1,32 @@
+// Copyright (c) 2021 Cisco Systems, Inc. and its affiliates
+// All rights reserved
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package util
+
+const (
+	// JobSpecFile is the multipart field name fledgectl uploads the job
+	// spec JSON under when it also attaches a signed manifest, so the
+	// apiserver can tell the job spec apart from TaskManifestFile and
+	// TaskManifestSigFile in the same request.
+	JobSpecFile = "jobSpec"
+
+	// TaskManifestFile is the name of the signed SHA-256 manifest of a
+	// task's code bundle, uploaded by fledgectl alongside the job spec and
+	// returned by getTask for fledgelet to verify before running the task.
+	TaskManifestFile = "manifest.json"
+
+	// TaskManifestSigFile is the ed25519 signature over TaskManifestFile.
+	TaskManifestSigFile = "manifest.sig"
+)